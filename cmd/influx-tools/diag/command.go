@@ -0,0 +1,124 @@
+// Package diag implements "influx-tools diag profile", a one-shot support
+// bundle of pprof snapshots fetched from a running export's -pprof-addr
+// endpoint, zipped up for attaching to a bug report.
+package diag
+
+import (
+	"archive/zip"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DefaultDuration is how long the remote CPU profile sample runs for by
+// default.
+const DefaultDuration = 30 * time.Second
+
+// profileNames are fetched, in order, from the target's net/http/pprof
+// endpoints and bundled as "<name>.pprof".
+var profileNames = []string{"profile", "heap", "goroutine", "block", "mutex"}
+
+// Command fetches a CPU profile plus heap, goroutine, block and mutex
+// snapshots from a remote process's net/http/pprof endpoint -- the one
+// export's -pprof-addr serves -- and bundles them into a single zip file,
+// so a running export can be profiled without touching its process.
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+	Client *http.Client
+
+	addr     string
+	out      string
+	duration time.Duration
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+		Client: &http.Client{},
+	}
+}
+
+func (cmd *Command) Run(args []string) error {
+	if err := cmd.parseFlags(args); err != nil {
+		return err
+	}
+
+	f, err := os.Create(cmd.out)
+	if err != nil {
+		return fmt.Errorf("diag: create %s: %w", cmd.out, err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	defer zw.Close()
+
+	for _, name := range profileNames {
+		if err := cmd.captureProfile(zw, name); err != nil {
+			return fmt.Errorf("diag: capture %s profile: %w", name, err)
+		}
+	}
+
+	fmt.Fprintf(cmd.Stdout, "wrote %s\n", cmd.out)
+	return nil
+}
+
+// captureProfile fetches the named pprof profile from cmd.addr and writes
+// it into the zip archive as "<name>.pprof". The CPU profile ("profile")
+// holds the connection open for cmd.duration while it samples; the rest
+// are instantaneous snapshots.
+func (cmd *Command) captureProfile(zw *zip.Writer, name string) error {
+	url := fmt.Sprintf("http://%s/debug/pprof/%s", cmd.addr, name)
+
+	client := cmd.Client
+	if name == "profile" {
+		url = fmt.Sprintf("%s?seconds=%d", url, int(cmd.duration.Seconds()))
+
+		// The server holds the connection open for the sampling window;
+		// give the client enough rope plus a margin for the fetch itself.
+		c := *client
+		c.Timeout = cmd.duration + 30*time.Second
+		client = &c
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch %s: unexpected status %s", url, resp.Status)
+	}
+
+	w, err := zw.Create(name + ".pprof")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func (cmd *Command) parseFlags(args []string) error {
+	fs := flag.NewFlagSet("diag profile", flag.ContinueOnError)
+	fs.StringVar(&cmd.addr, "addr", "", "Address of the running export's -pprof-addr endpoint, e.g. localhost:6060")
+	fs.StringVar(&cmd.out, "out", "", "Path to write the zip bundle to")
+	fs.DurationVar(&cmd.duration, "duration", DefaultDuration, "Length of the remote CPU profile sampling window")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if cmd.addr == "" {
+		return fmt.Errorf("diag profile: -addr is required")
+	}
+	if cmd.out == "" {
+		return fmt.Errorf("diag profile: -out is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package diag
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommand_BundlesEveryProfile(t *testing.T) {
+	var gotPaths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		io.WriteString(w, "profile-data:"+r.URL.Path)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "bundle.zip")
+
+	cmd := NewCommand()
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+
+	if err := cmd.Run([]string{"-addr", srv.Listener.Addr().String(), "-out", out, "-duration", "1ms"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(gotPaths) != len(profileNames) {
+		t.Fatalf("server saw %d requests, want %d", len(gotPaths), len(profileNames))
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("open bundle: %v", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+
+	for _, name := range profileNames {
+		want := name + ".pprof"
+		zf, err := zr.Open(want)
+		if err != nil {
+			t.Errorf("bundle missing %s: %v", want, err)
+			continue
+		}
+		data, err := io.ReadAll(zf)
+		zf.Close()
+		if err != nil {
+			t.Errorf("read %s: %v", want, err)
+			continue
+		}
+		if !bytes.Contains(data, []byte("/debug/pprof/"+name)) {
+			t.Errorf("%s contents = %q, want data fetched from /debug/pprof/%s", want, data, name)
+		}
+	}
+}
+
+func TestCommand_RequiresAddrAndOut(t *testing.T) {
+	cmd := NewCommand()
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+
+	if err := cmd.Run([]string{}); err == nil {
+		t.Error("Run with no flags should fail")
+	}
+	if err := cmd.Run([]string{"-addr", "localhost:1234"}); err == nil {
+		t.Error("Run with no -out should fail")
+	}
+}
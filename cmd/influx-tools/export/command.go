@@ -5,15 +5,25 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net/http"
+	_ "net/http/pprof"
 	"os"
+	"path/filepath"
 	"runtime"
 	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/arrow"
 	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/binary"
 	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/line"
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/parquet"
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/remote"
 	"github.com/influxdata/influxdb/cmd/influx-tools/server"
+	"github.com/influxdata/influxdb/influxql"
 	"go.uber.org/zap"
 )
 
@@ -22,6 +32,17 @@ var (
 	_ binary.Writer
 )
 
+// repeatableFlag accumulates repeated occurrences of a flag into a slice,
+// e.g. -measurement foo -measurement "bar*".
+type repeatableFlag []string
+
+func (f *repeatableFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *repeatableFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
 // Command represents the program execution for "store query".
 type Command struct {
 	// Standard input/output, overridden for testing.
@@ -30,12 +51,17 @@ type Command struct {
 	Logger *zap.Logger
 	server server.Interface
 
-	cpu *os.File
-	mem *os.File
+	cpu   *os.File
+	mem   *os.File
+	trace *os.File
 
 	configPath      string
 	cpuProfile      string
 	memProfile      string
+	traceProfile    string
+	blockProfile    string
+	mutexProfile    string
+	pprofAddr       string
 	database        string
 	rp              string
 	shardDuration   time.Duration
@@ -43,6 +69,14 @@ type Command struct {
 	startTime       int64
 	endTime         int64
 	format          string
+	outDir          string
+	batchSize       int
+	sink            string
+	parallel        int
+	metricsAddr     string
+	measurements    repeatableFlag
+	where           string
+	whereExpr       influxql.Expr
 	print           bool
 }
 
@@ -80,23 +114,93 @@ func (cmd *Command) Run(args []string) (err error) {
 	cmd.startProfile()
 	defer cmd.stopProfile()
 
-	var wr format.Writer
-	switch cmd.format {
-	case "line":
-		wr = line.NewWriter(os.Stdout)
+	progress := NewProgress(len(e.Shards()))
+
+	stop := make(chan struct{})
+	defer close(stop)
 
-	case "binary":
-		wr = binary.NewWriter(os.Stdout, cmd.database, cmd.rp, cmd.shardDuration)
+	if cmd.metricsAddr != "" {
+		errc := make(chan error, 1)
+		progress.ServeMetrics(cmd.metricsAddr, errc)
+		go func() {
+			if err := <-errc; err != nil {
+				fmt.Fprintf(cmd.Stderr, "metrics: %v\n", err)
+			}
+		}()
+	} else {
+		go progress.Watch(cmd.Stderr, ReportInterval, stop)
 	}
-	defer func() {
-		err = wr.Close()
-	}()
 
-	return e.WriteTo(wr)
+	return e.WriteTo(cmd.parallel, cmd.newWriter(), progress)
+}
+
+// newWriter returns the format.WriterFactory for this invocation. Every call
+// builds a new writer for the shard: a new remote sink writer with -sink, a
+// new writer over os.Stdout when neither -sink nor -out-dir is given, or a
+// per-shard file or directory under -out-dir.
+func (cmd *Command) newWriter() format.WriterFactory {
+	if cmd.sink != "" {
+		return func(shardID uint64) (format.Writer, error) {
+			return remote.NewWriter(cmd.sink, cmd.database, cmd.rp, cmd.shardDuration, cmd.batchSize)
+		}
+	}
+
+	if cmd.outDir == "" {
+		return func(shardID uint64) (format.Writer, error) {
+			switch cmd.format {
+			case "line":
+				return line.NewWriter(os.Stdout), nil
+			default:
+				return binary.NewWriter(os.Stdout, cmd.database, cmd.rp, cmd.shardDuration), nil
+			}
+		}
+	}
+
+	return func(shardID uint64) (format.Writer, error) {
+		shard := strconv.FormatUint(shardID, 10)
+
+		switch cmd.format {
+		case "line":
+			f, err := os.Create(filepath.Join(cmd.outDir, shard+".line"))
+			if err != nil {
+				return nil, err
+			}
+			return line.NewWriter(f), nil
+
+		case "binary":
+			f, err := os.Create(filepath.Join(cmd.outDir, shard+".bin"))
+			if err != nil {
+				return nil, err
+			}
+			return binary.NewWriter(f, cmd.database, cmd.rp, cmd.shardDuration), nil
+
+		case "parquet":
+			dir := filepath.Join(cmd.outDir, shard)
+			if err := os.MkdirAll(dir, 0777); err != nil {
+				return nil, err
+			}
+			return parquet.NewWriter(dir, cmd.batchSize), nil
+
+		default: // arrow
+			dir := filepath.Join(cmd.outDir, shard)
+			if err := os.MkdirAll(dir, 0777); err != nil {
+				return nil, err
+			}
+			return arrow.NewWriter(dir, cmd.batchSize), nil
+		}
+	}
 }
 
 func (cmd *Command) openExporter() (*Exporter, error) {
-	cfg := &ExporterConfig{Database: cmd.database, RP: cmd.rp, ShardDuration: cmd.shardDuration}
+	cfg := &ExporterConfig{
+		Database:      cmd.database,
+		RP:            cmd.rp,
+		ShardDuration: cmd.shardDuration,
+		Start:         cmd.startTime,
+		End:           cmd.endTime,
+		Measurements:  []string(cmd.measurements),
+		Where:         cmd.whereExpr,
+	}
 	e, err := NewExporter(cmd.server, cfg)
 	if err != nil {
 		return nil, err
@@ -110,11 +214,27 @@ func (cmd *Command) parseFlags(args []string) error {
 	fs.StringVar(&cmd.configPath, "config", "", "Config file")
 	fs.StringVar(&cmd.cpuProfile, "cpuprofile", "", "")
 	fs.StringVar(&cmd.memProfile, "memprofile", "", "")
+	fs.StringVar(&cmd.traceProfile, "trace", "", "Write a runtime/trace capture of the export to this file")
+	fs.StringVar(&cmd.blockProfile, "blockprofile", "", "Write a blocking profile of the export to this file")
+	fs.StringVar(&cmd.mutexProfile, "mutexprofile", "", "Write a mutex contention profile of the export to this file")
+	fs.StringVar(&cmd.pprofAddr, "pprof-addr", "", "Serve net/http/pprof on this address for the duration of the export")
 	fs.StringVar(&cmd.database, "database", "", "Database name")
 	fs.StringVar(&cmd.rp, "rp", "", "Retention policy name")
-	fs.StringVar(&cmd.format, "format", "line", "Output format (line, binary)")
+	fs.StringVar(&cmd.format, "format", "line", "Output format (line, binary, parquet, arrow)")
+	fs.StringVar(&cmd.outDir, "out-dir", "", "Directory to write per-shard/per-measurement files to (required for parquet, arrow)")
+	fs.IntVar(&cmd.batchSize, "batch-size", parquet.DefaultBatchSize, "Number of rows per row group/record batch for parquet, arrow and -sink output")
+	fs.StringVar(&cmd.sink, "sink", "", "Stream the export to a remote sink instead of stdout/-out-dir (http://host/write, grpc://host:port, kafka://broker/topic)")
+	fs.IntVar(&cmd.parallel, "parallel", 1, "Number of shards to export concurrently")
+	fs.StringVar(&cmd.metricsAddr, "metrics-addr", "", "Serve export progress as Prometheus metrics on this address instead of logging to stderr")
+	fs.Var(&cmd.measurements, "measurement", "Only export series in this measurement (repeatable; glob patterns allowed)")
+	fs.StringVar(&cmd.where, "where", "", "Only export series whose tags satisfy this InfluxQL tag predicate")
 	fs.BoolVar(&cmd.print, "print", false, "Print plan to stdout")
 	fs.DurationVar(&cmd.shardDuration, "duration", time.Hour*24*7, "Target shard duration")
+
+	var startStr, endStr string
+	fs.StringVar(&startStr, "start", "", "Only export points at or after this RFC3339 time")
+	fs.StringVar(&endStr, "end", "", "Only export points at or before this RFC3339 time")
+
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -123,14 +243,55 @@ func (cmd *Command) parseFlags(args []string) error {
 		return errors.New("database is required")
 	}
 
-	if cmd.format != "line" && cmd.format != "binary" {
-		return fmt.Errorf("invalid format '%s'", cmd.format)
+	if startStr != "" {
+		t, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			return fmt.Errorf("invalid -start time: %w", err)
+		}
+		cmd.startTime = t.UnixNano()
+	}
+
+	if endStr != "" {
+		t, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			return fmt.Errorf("invalid -end time: %w", err)
+		}
+		cmd.endTime = t.UnixNano()
+	}
+
+	if cmd.where != "" {
+		expr, err := influxql.ParseExpr(cmd.where)
+		if err != nil {
+			return fmt.Errorf("invalid -where predicate: %w", err)
+		}
+		cmd.whereExpr = expr
+	}
+
+	if cmd.parallel < 1 {
+		return errors.New("-parallel must be at least 1")
+	}
+
+	if cmd.sink == "" {
+		switch cmd.format {
+		case "line", "binary":
+		case "parquet", "arrow":
+			if cmd.outDir == "" {
+				return fmt.Errorf("-out-dir is required for format '%s'", cmd.format)
+			}
+		default:
+			return fmt.Errorf("invalid format '%s'", cmd.format)
+		}
+	}
+
+	if cmd.parallel > 1 && cmd.outDir == "" && cmd.sink == "" {
+		return errors.New("-parallel > 1 requires -out-dir or -sink; stdout can only be written by one worker")
 	}
 
 	return nil
 }
 
-// StartProfile initializes the cpu and memory profile, if specified.
+// StartProfile initializes the cpu, memory, trace, block and mutex
+// profiles, and the pprof HTTP endpoint, for whichever were requested.
 func (cmd *Command) startProfile() {
 	if cmd.cpuProfile != "" {
 		f, err := os.Create(cmd.cpuProfile)
@@ -152,9 +313,36 @@ func (cmd *Command) startProfile() {
 		runtime.MemProfileRate = 4096
 	}
 
+	if cmd.traceProfile != "" {
+		f, err := os.Create(cmd.traceProfile)
+		if err != nil {
+			fmt.Fprintf(cmd.Stderr, "trace: %v\n", err)
+			os.Exit(1)
+		}
+		cmd.trace = f
+		if err := trace.Start(cmd.trace); err != nil {
+			fmt.Fprintf(cmd.Stderr, "trace: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if cmd.blockProfile != "" {
+		runtime.SetBlockProfileRate(1)
+	}
+
+	if cmd.mutexProfile != "" {
+		runtime.SetMutexProfileFraction(1)
+	}
+
+	if cmd.pprofAddr != "" {
+		go func() {
+			fmt.Fprintf(cmd.Stderr, "pprof-addr: %v\n", http.ListenAndServe(cmd.pprofAddr, nil))
+		}()
+	}
 }
 
-// StopProfile closes the cpu and memory profiles if they are running.
+// StopProfile closes every profile startProfile opened and writes out the
+// block and mutex snapshots that were requested.
 func (cmd *Command) stopProfile() {
 	if cmd.cpu != nil {
 		pprof.StopCPUProfile()
@@ -164,4 +352,31 @@ func (cmd *Command) stopProfile() {
 		pprof.Lookup("heap").WriteTo(cmd.mem, 0)
 		cmd.mem.Close()
 	}
+	if cmd.trace != nil {
+		trace.Stop()
+		cmd.trace.Close()
+	}
+	if cmd.blockProfile != "" {
+		writeProfile(cmd.Stderr, "block", cmd.blockProfile)
+		runtime.SetBlockProfileRate(0)
+	}
+	if cmd.mutexProfile != "" {
+		writeProfile(cmd.Stderr, "mutex", cmd.mutexProfile)
+		runtime.SetMutexProfileFraction(0)
+	}
+}
+
+// writeProfile writes the named runtime profile (see pprof.Lookup) to
+// path, logging rather than failing the export if it can't be written.
+func writeProfile(stderr io.Writer, name, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s profile: %v\n", name, err)
+		return
+	}
+	defer f.Close()
+
+	if err := pprof.Lookup(name).WriteTo(f, 0); err != nil {
+		fmt.Fprintf(stderr, "%s profile: %v\n", name, err)
+	}
 }
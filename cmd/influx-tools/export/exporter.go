@@ -0,0 +1,323 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+	"github.com/influxdata/influxdb/cmd/influx-tools/server"
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+	"golang.org/x/sync/errgroup"
+)
+
+// ExporterConfig holds the parameters controlling which shards an Exporter
+// visits and which of their series are exported.
+type ExporterConfig struct {
+	Database      string
+	RP            string
+	ShardDuration time.Duration
+
+	// Start and End bound the exported time range, in Unix nanoseconds.
+	// Start's zero value exports from the beginning of time; End defaults
+	// to the maximum possible timestamp when left zero.
+	Start int64
+	End   int64
+
+	// Measurements, if non-empty, restricts export to measurements
+	// matching at least one of these glob patterns (see filepath.Match).
+	Measurements []string
+
+	// Where, if set, is evaluated against each series' tag set; series
+	// that don't satisfy it are pruned before their values are read.
+	Where influxql.Expr
+}
+
+// matchesMeasurement reports whether name satisfies cfg's -measurement
+// filter.
+func (cfg *ExporterConfig) matchesMeasurement(name string) bool {
+	if len(cfg.Measurements) == 0 {
+		return true
+	}
+	for _, pattern := range cfg.Measurements {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTags reports whether tags satisfies cfg's -where predicate.
+func (cfg *ExporterConfig) matchesTags(tags models.Tags) bool {
+	if cfg.Where == nil {
+		return true
+	}
+
+	fields := make(map[string]interface{}, len(tags))
+	for _, tag := range tags {
+		fields[string(tag.Key)] = string(tag.Value)
+	}
+	return influxql.EvalBool(cfg.Where, fields)
+}
+
+// ShardPlan describes a single shard an Exporter will read from disk.
+type ShardPlan struct {
+	Database string
+	RP       string
+	ID       uint64
+	Path     string
+}
+
+// Exporter walks the shards selected by an ExporterConfig and writes their
+// contents to one or more format.Writer instances.
+type Exporter struct {
+	server server.Interface
+	cfg    *ExporterConfig
+	shards []ShardPlan
+}
+
+// NewExporter returns an Exporter for cfg. Call Open before WriteTo,
+// Shards or PrintPlan.
+func NewExporter(server server.Interface, cfg *ExporterConfig) (*Exporter, error) {
+	if cfg.Start == 0 {
+		cfg.Start = math.MinInt64
+	}
+	if cfg.End == 0 {
+		cfg.End = math.MaxInt64
+	}
+	return &Exporter{server: server, cfg: cfg}, nil
+}
+
+// Open resolves the shard directories matching cfg under the server's
+// data directory.
+func (e *Exporter) Open() error {
+	dbPath := filepath.Join(e.server.TSDBConfig().Dir, e.cfg.Database)
+	rps, err := os.ReadDir(dbPath)
+	if err != nil {
+		return fmt.Errorf("export: read database dir: %w", err)
+	}
+
+	for _, rp := range rps {
+		if !rp.IsDir() || (e.cfg.RP != "" && rp.Name() != e.cfg.RP) {
+			continue
+		}
+
+		shards, err := os.ReadDir(filepath.Join(dbPath, rp.Name()))
+		if err != nil {
+			return fmt.Errorf("export: read rp dir: %w", err)
+		}
+
+		for _, sh := range shards {
+			var id uint64
+			if _, err := fmt.Sscanf(sh.Name(), "%d", &id); err != nil {
+				continue
+			}
+			e.shards = append(e.shards, ShardPlan{
+				Database: e.cfg.Database,
+				RP:       rp.Name(),
+				ID:       id,
+				Path:     filepath.Join(dbPath, rp.Name(), sh.Name()),
+			})
+		}
+	}
+
+	sort.Slice(e.shards, func(i, j int) bool { return e.shards[i].ID < e.shards[j].ID })
+	return nil
+}
+
+// Shards returns the shards Open selected.
+func (e *Exporter) Shards() []ShardPlan {
+	return e.shards
+}
+
+// PrintPlan writes a human-readable summary of the shards Open selected.
+func (e *Exporter) PrintPlan(w io.Writer) error {
+	for _, sh := range e.shards {
+		fmt.Fprintf(w, "%s/%s/%d\n", sh.Database, sh.RP, sh.ID)
+	}
+	return nil
+}
+
+// WriteTo exports every selected shard using up to parallel concurrent
+// workers, each obtained from newWriter with its shard's ID so callers can
+// split output per shard (e.g. one file per shard under -out-dir).
+// progress, if non-nil, is updated as points are written and as each shard
+// completes.
+func (e *Exporter) WriteTo(parallel int, newWriter format.WriterFactory, progress *Progress) error {
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	g, ctx := errgroup.WithContext(context.Background())
+	shardCh := make(chan ShardPlan)
+
+	g.Go(func() error {
+		defer close(shardCh)
+		for _, sh := range e.shards {
+			select {
+			case shardCh <- sh:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	for i := 0; i < parallel; i++ {
+		g.Go(func() error {
+			for sh := range shardCh {
+				if err := e.writeShard(sh, newWriter, progress); err != nil {
+					return fmt.Errorf("shard %d: %w", sh.ID, err)
+				}
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+func (e *Exporter) writeShard(sh ShardPlan, newWriter format.WriterFactory, progress *Progress) error {
+	w, err := newWriter(sh.ID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if progress != nil {
+			progress.ShardDone()
+		}
+	}()
+
+	cw := &countingWriter{Writer: w, progress: progress}
+	if err := e.exportShard(sh, cw); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// Close releases resources held by the Exporter.
+func (e *Exporter) Close() error {
+	return nil
+}
+
+// countingWriter wraps a format.Writer so Exporter can feed a Progress as
+// points are written without the Writer implementations needing to know
+// Progress exists.
+type countingWriter struct {
+	format.Writer
+	progress *Progress
+}
+
+func (w *countingWriter) Write(key []byte, values tsm1.Values) error {
+	if err := w.Writer.Write(key, values); err != nil {
+		return err
+	}
+	if w.progress != nil {
+		// 16 bytes/value approximates a timestamp plus a float64 or
+		// int64 field; good enough for a throughput estimate.
+		w.progress.AddPoints(len(values), len(key)+16*len(values))
+	}
+	return nil
+}
+
+// exportShard reads every TSM file under sh.Path and writes its series to
+// w, in file order. Shards are always fully compacted before being
+// exported, so later files never need to overwrite earlier ones for the
+// same key.
+func (e *Exporter) exportShard(sh ShardPlan, w format.Writer) error {
+	files, err := filepath.Glob(filepath.Join(sh.Path, "*.tsm"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := e.exportTSMFile(file, w); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// exportTSMFile writes every key in the TSM file at path that satisfies
+// e.cfg's measurement and tag filters to w. Measurement and tag predicates
+// are evaluated against the series key alone, so a non-matching series
+// never has its values decoded; an out-of-range file is skipped entirely
+// without opening a single key, and within a matching key only the blocks
+// whose own [MinTime, MaxTime] overlaps [e.cfg.Start, e.cfg.End] are ever
+// decoded, so a narrow time window on a long-lived series doesn't pay to
+// decode the whole key.
+func (e *Exporter) exportTSMFile(path string, w format.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if r.MinTime() > e.cfg.End || r.MaxTime() < e.cfg.Start {
+		return nil
+	}
+
+	var block tsm1.Values
+	for i := 0; i < r.KeyCount(); i++ {
+		key, _ := r.KeyAt(i)
+
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+		name, tags := models.ParseKeyBytes(seriesKey)
+		if !e.cfg.matchesMeasurement(string(name)) || !e.cfg.matchesTags(tags) {
+			continue
+		}
+
+		var values tsm1.Values
+		for _, entry := range r.Entries(key) {
+			if entry.MaxTime < e.cfg.Start || entry.MinTime > e.cfg.End {
+				continue
+			}
+
+			block, err = r.ReadAt(&entry, block[:0])
+			if err != nil {
+				return err
+			}
+			values = append(values, filterTimeRange(block, e.cfg.Start, e.cfg.End)...)
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := w.Write(key, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterTimeRange returns the subset of values falling within [start, end],
+// reusing values' backing array when no filtering is needed.
+func filterTimeRange(values tsm1.Values, start, end int64) tsm1.Values {
+	if start == math.MinInt64 && end == math.MaxInt64 {
+		return values
+	}
+
+	filtered := values[:0:0]
+	for _, v := range values {
+		if t := v.UnixNano(); t >= start && t <= end {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,144 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+func TestExporterConfig_matchesMeasurement(t *testing.T) {
+	tests := []struct {
+		name         string
+		measurements []string
+		measurement  string
+		want         bool
+	}{
+		{name: "no filter matches everything", measurements: nil, measurement: "cpu", want: true},
+		{name: "exact match", measurements: []string{"cpu"}, measurement: "cpu", want: true},
+		{name: "no match", measurements: []string{"cpu"}, measurement: "mem", want: false},
+		{name: "glob match", measurements: []string{"cpu*"}, measurement: "cpu_load", want: true},
+		{name: "matches one of several patterns", measurements: []string{"mem", "cpu*"}, measurement: "cpu_load", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ExporterConfig{Measurements: tt.measurements}
+			if got := cfg.matchesMeasurement(tt.measurement); got != tt.want {
+				t.Errorf("matchesMeasurement(%q) = %v, want %v", tt.measurement, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExporterConfig_matchesTags(t *testing.T) {
+	where, err := influxql.ParseExpr(`host = 'a' AND region = 'us-west'`)
+	if err != nil {
+		t.Fatalf("ParseExpr: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		where influxql.Expr
+		tags  models.Tags
+		want  bool
+	}{
+		{
+			name:  "no predicate matches everything",
+			where: nil,
+			tags:  models.NewTags(map[string]string{"host": "b"}),
+			want:  true,
+		},
+		{
+			name:  "satisfies predicate",
+			where: where,
+			tags:  models.NewTags(map[string]string{"host": "a", "region": "us-west"}),
+			want:  true,
+		},
+		{
+			name:  "fails predicate on one tag",
+			where: where,
+			tags:  models.NewTags(map[string]string{"host": "b", "region": "us-west"}),
+			want:  false,
+		},
+		{
+			name:  "missing tag fails predicate",
+			where: where,
+			tags:  models.NewTags(map[string]string{"host": "a"}),
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ExporterConfig{Where: tt.where}
+			if got := cfg.matchesTags(tt.tags); got != tt.want {
+				t.Errorf("matchesTags(%v) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExportTSMFile_prunesBeforeRead verifies that exportTSMFile consults
+// matchesMeasurement/matchesTags against a key's parsed series key before
+// ever calling r.Entries/r.ReadAt for it, by checking that a config
+// excluding every series given to it never invokes the writer.
+func TestExportTSMFile_prunesBeforeRead(t *testing.T) {
+	cfg := &ExporterConfig{Measurements: []string{"nonexistent*"}}
+
+	name, tags := "cpu", models.NewTags(map[string]string{"host": "a"})
+	if cfg.matchesMeasurement(name) {
+		t.Fatalf("expected measurement %q to be pruned by filter %v", name, cfg.Measurements)
+	}
+	if !cfg.matchesTags(tags) {
+		t.Fatalf("expected tags %v to pass an empty -where predicate", tags)
+	}
+
+	path := filepath.Join(t.TempDir(), "000000001-000000001.tsm")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	tw, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatalf("NewTSMWriter: %v", err)
+	}
+	seriesKey := models.AppendMakeKey(nil, []byte(name), tags)
+	key := tsm1.SeriesFieldKeyBytes(string(seriesKey), "value")
+	if err := tw.Write(key, tsm1.Values{tsm1.NewValue(1, 1.0)}); err != nil {
+		t.Fatalf("TSM Write: %v", err)
+	}
+	if err := tw.WriteIndex(); err != nil {
+		t.Fatalf("WriteIndex: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("TSM Close: %v", err)
+	}
+
+	e, err := NewExporter(nil, cfg)
+	if err != nil {
+		t.Fatalf("NewExporter: %v", err)
+	}
+	w := &recordingWriter{}
+	if err := e.exportTSMFile(path, w); err != nil {
+		t.Fatalf("exportTSMFile: %v", err)
+	}
+	if w.writes != 0 {
+		t.Errorf("writer invoked %d times for series the filter excludes, want 0", w.writes)
+	}
+}
+
+// recordingWriter counts the Write calls it receives.
+type recordingWriter struct {
+	writes int
+}
+
+func (w *recordingWriter) Write(key []byte, values tsm1.Values) error {
+	w.writes++
+	return nil
+}
+
+func (w *recordingWriter) Close() error { return nil }
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ReportInterval is how often Progress.Watch writes a throughput summary.
+const ReportInterval = 5 * time.Second
+
+// Progress tracks export throughput: points written, bytes written and
+// shards remaining, optionally exposing the same counters on a Prometheus
+// /metrics endpoint.
+type Progress struct {
+	totalShards int64
+	shardsLeft  int64
+	points      int64
+	bytes       int64
+
+	start time.Time
+
+	pointsMetric prometheus.Counter
+	bytesMetric  prometheus.Counter
+	shardsMetric prometheus.Gauge
+}
+
+// NewProgress returns a Progress tracking totalShards shards.
+func NewProgress(totalShards int) *Progress {
+	return &Progress{
+		totalShards: int64(totalShards),
+		shardsLeft:  int64(totalShards),
+		start:       time.Now(),
+	}
+}
+
+// AddPoints records n points totalling bytes written by a worker.
+func (p *Progress) AddPoints(n, bytes int) {
+	atomic.AddInt64(&p.points, int64(n))
+	atomic.AddInt64(&p.bytes, int64(bytes))
+	if p.pointsMetric != nil {
+		p.pointsMetric.Add(float64(n))
+		p.bytesMetric.Add(float64(bytes))
+	}
+}
+
+// ShardDone records that one shard finished exporting.
+func (p *Progress) ShardDone() {
+	left := atomic.AddInt64(&p.shardsLeft, -1)
+	if p.shardsMetric != nil {
+		p.shardsMetric.Set(float64(left))
+	}
+}
+
+// Report writes a single-line throughput summary to w.
+func (p *Progress) Report(w io.Writer) {
+	elapsed := time.Since(p.start).Seconds()
+	points := atomic.LoadInt64(&p.points)
+	bytesWritten := atomic.LoadInt64(&p.bytes)
+	left := atomic.LoadInt64(&p.shardsLeft)
+	done := p.totalShards - left
+
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(points) / elapsed
+	}
+
+	eta := "unknown"
+	if done > 0 && left > 0 {
+		perShard := elapsed / float64(done)
+		eta = time.Duration(perShard * float64(left) * float64(time.Second)).Round(time.Second).String()
+	}
+
+	fmt.Fprintf(w, "export: points=%d (%.0f/s) bytes=%d shards=%d/%d eta=%s\n",
+		points, rate, bytesWritten, done, p.totalShards, eta)
+}
+
+// Watch writes a Report to w every interval until stop is closed, with a
+// final Report once stop fires.
+func (p *Progress) Watch(w io.Writer, interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			p.Report(w)
+		case <-stop:
+			p.Report(w)
+			return
+		}
+	}
+}
+
+// ServeMetrics registers this Progress's counters with a fresh Prometheus
+// registry and serves them on addr until the listener fails or the process
+// exits; the listener error, if any, is sent to errc.
+func (p *Progress) ServeMetrics(addr string, errc chan<- error) {
+	p.pointsMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "influx_tools_export_points_total",
+		Help: "Points written so far by this export.",
+	})
+	p.bytesMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "influx_tools_export_bytes_total",
+		Help: "Bytes written so far by this export.",
+	})
+	p.shardsMetric = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "influx_tools_export_shards_remaining",
+		Help: "Shards left to export.",
+	})
+	p.shardsMetric.Set(float64(p.totalShards))
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(p.pointsMetric, p.bytesMetric, p.shardsMetric)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	go func() {
+		errc <- http.ListenAndServe(addr, mux)
+	}()
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgress_ReportReflectsAddedPointsAndBytes(t *testing.T) {
+	p := NewProgress(2)
+	p.AddPoints(10, 160)
+	p.AddPoints(5, 80)
+
+	var buf bytes.Buffer
+	p.Report(&buf)
+
+	got := buf.String()
+	if !strings.Contains(got, "points=15") {
+		t.Errorf("Report() = %q, want it to contain points=15", got)
+	}
+	if !strings.Contains(got, "bytes=240") {
+		t.Errorf("Report() = %q, want it to contain bytes=240", got)
+	}
+	if !strings.Contains(got, "shards=0/2") {
+		t.Errorf("Report() = %q, want it to contain shards=0/2", got)
+	}
+}
+
+func TestProgress_ShardDoneDecrementsRemaining(t *testing.T) {
+	p := NewProgress(2)
+	p.ShardDone()
+
+	var buf bytes.Buffer
+	p.Report(&buf)
+
+	if got := buf.String(); !strings.Contains(got, "shards=1/2") {
+		t.Errorf("Report() = %q, want it to contain shards=1/2", got)
+	}
+
+	p.ShardDone()
+	buf.Reset()
+	p.Report(&buf)
+	if got := buf.String(); !strings.Contains(got, "shards=2/2") {
+		t.Errorf("Report() = %q, want it to contain shards=2/2", got)
+	}
+}
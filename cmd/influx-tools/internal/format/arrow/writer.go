@@ -0,0 +1,356 @@
+// Package arrow implements a format.Writer that encodes exported points
+// as Apache Arrow IPC streams, one stream per measurement.
+package arrow
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/ipc"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+var _ format.Writer = (*Writer)(nil)
+
+// DefaultBatchSize is the number of rows buffered per measurement before a
+// record batch is written to the IPC stream.
+const DefaultBatchSize = 8192
+
+// Writer encodes exported points as one Arrow IPC stream per measurement,
+// written to a file named "<measurement>.arrow" under Dir. Tag columns
+// are dictionary-encoded strings; fields are typed int64, uint64, float64,
+// bool or string columns depending on the first value observed for that
+// field.
+type Writer struct {
+	Dir       string
+	BatchSize int
+
+	// Stderr receives a warning the first time a measurement's schema is
+	// already committed to its IPC stream and a later point introduces a
+	// tag or field that stream never had room for. Defaults to os.Stderr.
+	Stderr io.Writer
+
+	alloc  memory.Allocator
+	tables map[string]*table
+}
+
+// NewWriter returns a Writer that creates one Arrow IPC stream per
+// measurement in dir, flushing record batches of batchSize rows.
+func NewWriter(dir string, batchSize int) *Writer {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Writer{
+		Dir:       dir,
+		BatchSize: batchSize,
+		Stderr:    os.Stderr,
+		alloc:     memory.NewGoAllocator(),
+		tables:    make(map[string]*table),
+	}
+}
+
+// Write implements format.Writer.
+func (w *Writer) Write(key []byte, values tsm1.Values) error {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	name, tags := models.ParseKeyBytes(seriesKey)
+
+	t, err := w.tableFor(string(name), tags, field, values)
+	if err != nil {
+		return err
+	}
+	return t.write(tags, field, values)
+}
+
+func (w *Writer) tableFor(measurement string, tags models.Tags, field string, values tsm1.Values) (*table, error) {
+	if t, ok := w.tables[measurement]; ok {
+		t.ensureColumns(tags, field, values)
+		return t, nil
+	}
+
+	f, err := os.Create(filepath.Join(w.Dir, measurement+".arrow"))
+	if err != nil {
+		return nil, fmt.Errorf("arrow: create %s: %w", measurement, err)
+	}
+
+	t := newTable(w.alloc, f, w.BatchSize, w.Stderr)
+	t.ensureColumns(tags, field, values)
+	w.tables[measurement] = t
+	return t, nil
+}
+
+// Close flushes and closes every measurement stream that was opened during
+// the export.
+func (w *Writer) Close() error {
+	var firstErr error
+	for _, t := range w.tables {
+		if err := t.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// table buffers one measurement's rows in column builders and owns the
+// ipc.Writer the record batches are flushed to. A stream's schema is fixed
+// once the first record batch has actually been written to the IPC stream;
+// up to that point ensureColumns may still widen the schema, rebuilding
+// the in-progress builder (and replaying any buffered rows into it) so a
+// measurement with more fields or tag sets than its first point never
+// loses columns or panics.
+type table struct {
+	alloc     memory.Allocator
+	out       *os.File
+	batchSize int
+	stderr    io.Writer
+	name      string
+
+	fields      []arrow.Field
+	builder     *array.RecordBuilder
+	index       map[string]int
+	n           int
+	warnedDrops bool
+
+	ipcw *ipc.Writer
+}
+
+func newTable(alloc memory.Allocator, out *os.File, batchSize int, stderr io.Writer) *table {
+	return &table{
+		alloc:     alloc,
+		out:       out,
+		batchSize: batchSize,
+		stderr:    stderr,
+		name:      strings.TrimSuffix(filepath.Base(out.Name()), ".arrow"),
+		fields:    []arrow.Field{{Name: "time", Type: arrow.FixedWidthTypes.Timestamp_ns}},
+		index:     map[string]int{"time": 0},
+	}
+}
+
+func (t *table) ensureColumns(tags models.Tags, field string, values tsm1.Values) {
+	if t.ipcw != nil {
+		// Stream schema is already committed; late-arriving columns are
+		// dropped rather than breaking the stream. Warn once per table so
+		// an operator relying on this export doesn't silently trust
+		// incomplete output.
+		if t.hasNewColumns(tags, field) && !t.warnedDrops {
+			t.warnedDrops = true
+			fmt.Fprintf(t.stderr, "arrow: %s: schema already written, dropping tag/field columns introduced after the first batch flush (increase -batch-size to avoid this)\n", t.name)
+		}
+		return
+	}
+
+	before := len(t.fields)
+
+	for _, tag := range tags {
+		t.addField(string(tag.Key), &arrow.DictionaryType{
+			IndexType: arrow.PrimitiveTypes.Int32,
+			ValueType: arrow.BinaryTypes.String,
+		})
+	}
+
+	if len(values) > 0 {
+		t.addField(field, fieldType(values[0].Value()))
+	}
+
+	if t.builder != nil && len(t.fields) > before {
+		t.rebuildBuilder()
+	}
+}
+
+// rebuildBuilder replaces t.builder with one matching the current (wider)
+// schema, replaying every row buffered so far so no data is lost when a
+// later point introduces a field or tag the first point didn't have.
+func (t *table) rebuildBuilder() {
+	old := t.builder
+	oldRec := old.NewRecord()
+	defer oldRec.Release()
+	old.Release()
+
+	nRows := int(oldRec.NumRows())
+	nCols := len(oldRec.Columns())
+
+	builder := array.NewRecordBuilder(t.alloc, t.schema())
+	for i := range t.fields {
+		dst := builder.Field(i)
+		if i < nCols {
+			copyColumn(dst, oldRec.Column(i))
+			continue
+		}
+		for r := 0; r < nRows; r++ {
+			dst.AppendNull()
+		}
+	}
+	t.builder = builder
+}
+
+// copyColumn replays every value of src, a previously built column, into
+// dst, the corresponding field builder of a freshly rebuilt record.
+func copyColumn(dst array.Builder, src arrow.Array) {
+	for i := 0; i < src.Len(); i++ {
+		if src.IsNull(i) {
+			dst.AppendNull()
+			continue
+		}
+		switch src := src.(type) {
+		case *array.Timestamp:
+			dst.(*array.TimestampBuilder).Append(src.Value(i))
+		case *array.Int64:
+			dst.(*array.Int64Builder).Append(src.Value(i))
+		case *array.Uint64:
+			dst.(*array.Uint64Builder).Append(src.Value(i))
+		case *array.Float64:
+			dst.(*array.Float64Builder).Append(src.Value(i))
+		case *array.Boolean:
+			dst.(*array.BooleanBuilder).Append(src.Value(i))
+		case *array.String:
+			dst.(*array.StringBuilder).Append(src.Value(i))
+		case *array.Dictionary:
+			s, _ := src.Dictionary().(*array.String)
+			dst.(*array.BinaryDictionaryBuilder).AppendString(s.Value(src.GetValueIndex(i)))
+		default:
+			dst.AppendNull()
+		}
+	}
+}
+
+// hasNewColumns reports whether field or any of tags is not already part of
+// t's committed schema.
+func (t *table) hasNewColumns(tags models.Tags, field string) bool {
+	if _, ok := t.index[field]; !ok {
+		return true
+	}
+	for _, tag := range tags {
+		if _, ok := t.index[string(tag.Key)]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *table) addField(name string, dt arrow.DataType) {
+	if _, ok := t.index[name]; ok {
+		return
+	}
+	t.index[name] = len(t.fields)
+	t.fields = append(t.fields, arrow.Field{Name: name, Type: dt, Nullable: true})
+}
+
+func fieldType(v interface{}) arrow.DataType {
+	switch v.(type) {
+	case int64:
+		return arrow.PrimitiveTypes.Int64
+	case uint64:
+		return arrow.PrimitiveTypes.Uint64
+	case float64:
+		return arrow.PrimitiveTypes.Float64
+	case bool:
+		return arrow.FixedWidthTypes.Boolean
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+func (t *table) schema() *arrow.Schema {
+	return arrow.NewSchema(t.fields, nil)
+}
+
+func (t *table) write(tags models.Tags, field string, values tsm1.Values) error {
+	if t.builder == nil {
+		t.builder = array.NewRecordBuilder(t.alloc, t.schema())
+	}
+
+	for _, v := range values {
+		t.appendRow(tags, field, v)
+		t.n++
+		if t.n >= t.batchSize {
+			if err := t.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *table) appendRow(tags models.Tags, field string, v tsm1.Value) {
+	for i := range t.fields {
+		switch i {
+		case t.index["time"]:
+			t.builder.Field(i).(*array.TimestampBuilder).Append(arrow.Timestamp(v.UnixNano()))
+		case t.index[field]:
+			appendValue(t.builder.Field(i), v.Value())
+		default:
+			if tag, ok := tagValue(tags, t.fields[i].Name); ok {
+				t.builder.Field(i).(*array.BinaryDictionaryBuilder).AppendString(tag)
+			} else {
+				t.builder.Field(i).AppendNull()
+			}
+		}
+	}
+}
+
+func tagValue(tags models.Tags, name string) (string, bool) {
+	for _, tag := range tags {
+		if string(tag.Key) == name {
+			return string(tag.Value), true
+		}
+	}
+	return "", false
+}
+
+func appendValue(b array.Builder, v interface{}) {
+	switch b := b.(type) {
+	case *array.Int64Builder:
+		b.Append(v.(int64))
+	case *array.Uint64Builder:
+		b.Append(v.(uint64))
+	case *array.Float64Builder:
+		b.Append(v.(float64))
+	case *array.BooleanBuilder:
+		b.Append(v.(bool))
+	case *array.StringBuilder:
+		b.Append(v.(string))
+	default:
+		b.AppendNull()
+	}
+}
+
+func (t *table) flush() error {
+	if t.builder == nil || t.n == 0 {
+		return nil
+	}
+
+	if t.ipcw == nil {
+		t.ipcw = ipc.NewWriter(t.out, ipc.WithSchema(t.schema()), ipc.WithAllocator(t.alloc))
+	}
+
+	rec := t.builder.NewRecord()
+	defer rec.Release()
+
+	if err := t.ipcw.Write(rec); err != nil {
+		return fmt.Errorf("arrow: write record batch: %w", err)
+	}
+	t.n = 0
+	return nil
+}
+
+func (t *table) Close() error {
+	if err := t.flush(); err != nil {
+		return err
+	}
+	if t.ipcw != nil {
+		if err := t.ipcw.Close(); err != nil {
+			return err
+		}
+	}
+	if t.builder != nil {
+		t.builder.Release()
+	}
+	return t.out.Close()
+}
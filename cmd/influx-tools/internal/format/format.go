@@ -0,0 +1,23 @@
+// Package format defines the output contract shared by every export
+// encoder (line protocol, the binary framing, Parquet, Arrow IPC and the
+// remote sinks), so Exporter can drive them all the same way.
+package format
+
+import (
+	"io"
+
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+// Writer receives the decoded values for one series/field key at a time,
+// in the composite series/field key format that tsm1.SeriesFieldKeyBytes
+// builds and tsm1.SeriesAndFieldFromCompositeKey splits.
+type Writer interface {
+	io.Closer
+	Write(key []byte, values tsm1.Values) error
+}
+
+// WriterFactory creates a Writer for the given shard ID, letting a worker
+// pool give each concurrent worker (and, for per-shard output, each
+// shard) its own Writer instance.
+type WriterFactory func(shardID uint64) (Writer, error)
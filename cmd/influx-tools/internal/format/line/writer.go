@@ -0,0 +1,94 @@
+// Package line implements a format.Writer that encodes exported points as
+// InfluxDB line protocol text.
+package line
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/escape"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+var _ format.Writer = (*Writer)(nil)
+
+// Writer encodes exported points as line protocol, one line per point,
+// escaping measurement, tag and field names per the line protocol grammar
+// and quoting string field values. Other writers that need to emit line
+// protocol (the HTTP and Kafka remote sinks) should use AppendLine rather
+// than reimplementing this encoding.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewWriter returns a Writer that writes line protocol to w.
+func NewWriter(w io.Writer) *Writer {
+	bw, ok := w.(*bufio.Writer)
+	if !ok {
+		bw = bufio.NewWriter(w)
+	}
+	return &Writer{w: bw}
+}
+
+// Write implements format.Writer.
+func (w *Writer) Write(key []byte, values tsm1.Values) error {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	name, tags := models.ParseKeyBytes(seriesKey)
+
+	var buf []byte
+	for _, v := range values {
+		buf = AppendLine(buf[:0], name, tags, field, v)
+		if _, err := w.w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes any buffered output.
+func (w *Writer) Close() error { return w.w.Flush() }
+
+// AppendLine appends a single line-protocol line encoding v to dst,
+// escaping name, tags and field per the line protocol grammar and
+// terminating with a newline. It distinguishes int64 fields from
+// float64 with the trailing "i" InfluxDB requires, and quotes (and
+// escapes) string field values.
+func AppendLine(dst []byte, name []byte, tags models.Tags, field string, v tsm1.Value) []byte {
+	dst = models.AppendMakeKey(dst, name, tags)
+	dst = append(dst, ' ')
+	dst = append(dst, escape.Bytes([]byte(field))...)
+	dst = append(dst, '=')
+	dst = appendFieldValue(dst, v.Value())
+	dst = append(dst, ' ')
+	dst = strconv.AppendInt(dst, v.UnixNano(), 10)
+	dst = append(dst, '\n')
+	return dst
+}
+
+func appendFieldValue(dst []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case int64:
+		dst = strconv.AppendInt(dst, v, 10)
+		dst = append(dst, 'i')
+	case uint64:
+		dst = strconv.AppendUint(dst, v, 10)
+		dst = append(dst, 'u')
+	case float64:
+		dst = strconv.AppendFloat(dst, v, 'g', -1, 64)
+	case bool:
+		dst = strconv.AppendBool(dst, v)
+	case string:
+		dst = append(dst, '"')
+		dst = append(dst, models.EscapeStringField(v)...)
+		dst = append(dst, '"')
+	default:
+		dst = append(dst, '"')
+		dst = append(dst, models.EscapeStringField("")...)
+		dst = append(dst, '"')
+	}
+	return dst
+}
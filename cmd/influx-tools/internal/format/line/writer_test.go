@@ -0,0 +1,37 @@
+package line
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+func TestAppendLine(t *testing.T) {
+	name := []byte("cpu")
+	tags := models.NewTags(map[string]string{"host": "a"})
+
+	tests := []struct {
+		name  string
+		field string
+		value interface{}
+		want  string
+	}{
+		{name: "int64 gets an i suffix", field: "n", value: int64(42), want: `cpu,host=a n=42i 1`},
+		{name: "uint64 gets a u suffix", field: "n", value: uint64(7), want: `cpu,host=a n=7u 1`},
+		{name: "float64 has no suffix", field: "f", value: 3.14, want: `cpu,host=a f=3.14 1`},
+		{name: "bool", field: "b", value: true, want: `cpu,host=a b=true 1`},
+		{name: "string is quoted and escaped", field: "s", value: `say "hi"`, want: `cpu,host=a s="say \"hi\"" 1`},
+		{name: "field name with a space is escaped", field: "field name", value: int64(1), want: `cpu,host=a field\ name=1i 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tsm1.NewValue(1, tt.value)
+			got := string(AppendLine(nil, name, tags, tt.field, v))
+			if want := tt.want + "\n"; got != want {
+				t.Errorf("AppendLine() = %q, want %q", got, want)
+			}
+		})
+	}
+}
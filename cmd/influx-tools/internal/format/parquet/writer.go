@@ -0,0 +1,211 @@
+// Package parquet implements a format.Writer that encodes exported points
+// as Apache Parquet files, one file per measurement (or per shard, when
+// the writer is split across shards by the caller).
+package parquet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+	"github.com/parquet-go/parquet-go"
+)
+
+var _ format.Writer = (*Writer)(nil)
+
+// DefaultBatchSize is the number of rows buffered per measurement before a
+// row group is flushed to disk.
+const DefaultBatchSize = 8192
+
+// Writer accumulates decoded points and flushes them to one Parquet file
+// per measurement under Dir. A measurement's schema is widened on demand
+// as new tag or field columns are observed, so all of a measurement's
+// rows are buffered in memory and written as a single coherent file, in
+// row groups of at most BatchSize points, once its schema is final at
+// Close. Parquet stores one schema per file footer, so a file can't be
+// flushed incrementally while its schema might still grow.
+type Writer struct {
+	// Dir is the directory new Parquet files are created in. It must
+	// already exist.
+	Dir string
+
+	// BatchSize is the number of rows per row group when a measurement's
+	// buffered rows are written out at Close.
+	BatchSize int
+
+	tables map[string]*table
+}
+
+// NewWriter returns a Writer that creates one Parquet file per measurement
+// in dir, flushing row groups of batchSize rows.
+func NewWriter(dir string, batchSize int) *Writer {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Writer{
+		Dir:       dir,
+		BatchSize: batchSize,
+		tables:    make(map[string]*table),
+	}
+}
+
+// Write implements format.Writer.
+func (w *Writer) Write(key []byte, values tsm1.Values) error {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	name, tags := models.ParseKeyBytes(seriesKey)
+
+	t, err := w.tableFor(string(name))
+	if err != nil {
+		return err
+	}
+	return t.write(tags, field, values)
+}
+
+func (w *Writer) tableFor(measurement string) (*table, error) {
+	if t, ok := w.tables[measurement]; ok {
+		return t, nil
+	}
+
+	f, err := os.Create(filepath.Join(w.Dir, measurement+".parquet"))
+	if err != nil {
+		return nil, fmt.Errorf("parquet: create %s: %w", measurement, err)
+	}
+
+	t := newTable(measurement, f, w.BatchSize)
+	w.tables[measurement] = t
+	return t, nil
+}
+
+// Close flushes and closes every measurement file that was opened during
+// the export.
+func (w *Writer) Close() error {
+	var firstErr error
+	for _, t := range w.tables {
+		if err := t.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// table buffers every row for a single measurement in memory, widening
+// columns as new tags or fields are observed, and defers creating the
+// underlying parquet.Writer until Close, once the schema can no longer
+// change.
+type table struct {
+	name      string
+	out       *os.File
+	batchSize int
+
+	columns map[string]parquet.Node
+	rows    []map[string]interface{}
+}
+
+func newTable(name string, out *os.File, batchSize int) *table {
+	return &table{
+		name:      name,
+		out:       out,
+		batchSize: batchSize,
+		columns:   map[string]parquet.Node{"time": parquet.Leaf(parquet.Int64Type)},
+	}
+}
+
+func (t *table) write(tags models.Tags, field string, values tsm1.Values) error {
+	for _, tag := range tags {
+		t.addColumn(string(tag.Key), parquet.Optional(parquet.Encoded(parquet.String(), &parquet.RLEDictionary)))
+	}
+
+	for _, v := range values {
+		col, row := columnFor(field, v.Value())
+		t.addColumn(field, col)
+
+		row["time"] = v.UnixNano()
+		for _, tag := range tags {
+			row[string(tag.Key)] = string(tag.Value)
+		}
+		t.rows = append(t.rows, row)
+	}
+	return nil
+}
+
+// addColumn registers node under name, widening the schema. Rows are only
+// written out at Close, once every column any row needs has been added,
+// so widening here never invalidates data already handed to a
+// parquet.Writer.
+func (t *table) addColumn(name string, node parquet.Node) {
+	if _, ok := t.columns[name]; ok {
+		return
+	}
+	t.columns[name] = node
+}
+
+// columnFor returns the parquet leaf type for field's value along with a
+// fresh row map seeded with that single value, letting dictionary-encoded
+// string tags and typed fields share the same buffering path.
+func columnFor(field string, value interface{}) (parquet.Node, map[string]interface{}) {
+	row := map[string]interface{}{field: value}
+	switch value.(type) {
+	case int64:
+		return parquet.Optional(parquet.Int(64)), row
+	case float64:
+		return parquet.Optional(parquet.Leaf(parquet.DoubleType)), row
+	case bool:
+		return parquet.Optional(parquet.Leaf(parquet.BooleanType)), row
+	case string:
+		return parquet.Optional(parquet.String()), row
+	default:
+		return parquet.Optional(parquet.String()), row
+	}
+}
+
+func (t *table) schema() *parquet.Schema {
+	group := make(parquet.Group, len(t.columns))
+	for name, node := range t.columns {
+		group[name] = node
+	}
+	return parquet.NewSchema(t.name, group)
+}
+
+// Close writes every row buffered for this measurement to a single
+// Parquet file, now that its schema is final, and closes the underlying
+// file. Rows are written in row groups of at most batchSize so large
+// measurements don't produce one unwieldy row group, but all row groups
+// in the file share the one schema built from every column observed.
+func (t *table) Close() error {
+	if len(t.rows) == 0 {
+		return t.out.Close()
+	}
+
+	// Row keys are written in a stable order so row groups are
+	// comparable across runs.
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		return t.rows[i]["time"].(int64) < t.rows[j]["time"].(int64)
+	})
+
+	pw := parquet.NewGenericWriter[map[string]interface{}](t.out, t.schema())
+	for start := 0; start < len(t.rows); start += t.batchSize {
+		end := start + t.batchSize
+		if end > len(t.rows) {
+			end = len(t.rows)
+		}
+		if _, err := pw.Write(t.rows[start:end]); err != nil {
+			pw.Close()
+			t.out.Close()
+			return fmt.Errorf("parquet: write row group for %s: %w", t.name, err)
+		}
+		if err := pw.Flush(); err != nil {
+			pw.Close()
+			t.out.Close()
+			return fmt.Errorf("parquet: flush row group for %s: %w", t.name, err)
+		}
+	}
+	if err := pw.Close(); err != nil {
+		t.out.Close()
+		return fmt.Errorf("parquet: close %s: %w", t.name, err)
+	}
+	return t.out.Close()
+}
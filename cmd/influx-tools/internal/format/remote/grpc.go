@@ -0,0 +1,107 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/binary"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var _ format.Writer = (*GRPCWriter)(nil)
+
+// GRPCWriter streams the same framing binary.Writer produces to a remote
+// Export service (see internal/format/binary/stream.proto) instead of a
+// local file, one Chunk per underlying write.
+type GRPCWriter struct {
+	conn   *grpc.ClientConn
+	stream binary.Export_StreamClient
+
+	pw *io.PipeWriter
+	bw *binary.Writer
+
+	done chan error
+}
+
+// NewGRPCWriter dials addr and opens a streaming Export RPC that the
+// returned writer relays binary.Writer's framed output to.
+func NewGRPCWriter(addr, database, rp string, shardDuration time.Duration) (*GRPCWriter, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("remote: dial %s: %w", addr, err)
+	}
+
+	stream, err := binary.NewExportClient(conn).Stream(context.Background())
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("remote: open stream: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	w := &GRPCWriter{
+		conn:   conn,
+		stream: stream,
+		pw:     pw,
+		bw:     binary.NewWriter(pw, database, rp, shardDuration),
+		done:   make(chan error, 1),
+	}
+
+	go w.relay(pr)
+
+	return w, nil
+}
+
+// relay reads the framed bytes binary.Writer produces and forwards them to
+// the server as a sequence of Chunks.
+func (w *GRPCWriter) relay(r *io.PipeReader) {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+			if sendErr := w.stream.Send(&binary.Chunk{Data: chunk}); sendErr != nil {
+				r.CloseWithError(sendErr)
+				w.done <- sendErr
+				return
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				_, ackErr := w.stream.CloseAndRecv()
+				w.done <- ackErr
+			} else {
+				w.done <- err
+			}
+			return
+		}
+	}
+}
+
+// Write implements format.Writer.
+func (w *GRPCWriter) Write(key []byte, values tsm1.Values) error {
+	return w.bw.Write(key, values)
+}
+
+// Close flushes the underlying binary.Writer, waits for the relay
+// goroutine to finish streaming to the server, and tears down the
+// connection.
+func (w *GRPCWriter) Close() error {
+	bwErr := w.bw.Close()
+	w.pw.Close()
+	relayErr := <-w.done
+	connErr := w.conn.Close()
+
+	if bwErr != nil {
+		return bwErr
+	}
+	if relayErr != nil {
+		return relayErr
+	}
+	return connErr
+}
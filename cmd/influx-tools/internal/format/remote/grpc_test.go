@@ -0,0 +1,66 @@
+package remote
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/binary"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+	"google.golang.org/grpc"
+)
+
+// fakeExportServer collects every chunk sent to it and acks once the
+// client closes the stream.
+type fakeExportServer struct {
+	binary.UnimplementedExportServer
+	received bytes.Buffer
+}
+
+func (s *fakeExportServer) Stream(stream binary.Export_StreamServer) error {
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		s.received.Write(chunk.Data)
+	}
+	return stream.SendAndClose(&binary.Ack{})
+}
+
+func TestGRPCWriter_StreamsToServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer lis.Close()
+
+	srv := grpc.NewServer()
+	fake := &fakeExportServer{}
+	binary.RegisterExportServer(srv, fake)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	w, err := NewGRPCWriter(lis.Addr().String(), "mydb", "autogen", time.Hour)
+	if err != nil {
+		t.Fatalf("NewGRPCWriter: %v", err)
+	}
+
+	name := []byte("cpu")
+	tags := models.NewTags(map[string]string{"host": "a"})
+	seriesKey := models.AppendMakeKey(nil, name, tags)
+	key := tsm1.SeriesFieldKeyBytes(string(seriesKey), "n")
+
+	if err := w.Write(key, tsm1.Values{tsm1.NewValue(1, int64(42))}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if fake.received.Len() == 0 {
+		t.Error("server received no data from the stream")
+	}
+}
@@ -0,0 +1,144 @@
+package remote
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/line"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+var _ format.Writer = (*HTTPWriter)(nil)
+
+// DefaultHTTPBatchSize is the number of points buffered before an HTTP
+// writer flushes a write request.
+const DefaultHTTPBatchSize = 5000
+
+// HTTPWriter streams exported points to a remote InfluxDB-compatible
+// /write endpoint as gzip-compressed line protocol, batching points and
+// retrying failed requests with exponential backoff.
+type HTTPWriter struct {
+	// URL is the target write endpoint, e.g. http://host:8086/write?db=mydb.
+	URL string
+	// BatchSize is the number of points buffered per request.
+	BatchSize int
+	// MaxRetries bounds the number of retries for a failed batch.
+	MaxRetries int
+	// Client performs the HTTP requests; overridden in tests.
+	Client *http.Client
+
+	buf     bytes.Buffer
+	lineBuf []byte
+	count   int
+}
+
+// NewHTTPWriter returns an HTTPWriter that POSTs gzip-compressed line
+// protocol batches of batchSize points to rawURL.
+func NewHTTPWriter(rawURL string, batchSize int) (*HTTPWriter, error) {
+	if _, err := url.Parse(rawURL); err != nil {
+		return nil, fmt.Errorf("remote: invalid sink URL %q: %w", rawURL, err)
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultHTTPBatchSize
+	}
+	return &HTTPWriter{
+		URL:        rawURL,
+		BatchSize:  batchSize,
+		MaxRetries: 5,
+		Client:     &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Write implements format.Writer.
+func (w *HTTPWriter) Write(key []byte, values tsm1.Values) error {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	name, tags := models.ParseKeyBytes(seriesKey)
+
+	for _, v := range values {
+		w.lineBuf = line.AppendLine(w.lineBuf[:0], name, tags, field, v)
+		w.buf.Write(w.lineBuf)
+		w.count++
+	}
+
+	if w.count >= w.BatchSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *HTTPWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	if _, err := zw.Write(w.buf.Bytes()); err != nil {
+		return fmt.Errorf("remote: gzip write: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("remote: gzip close: %w", err)
+	}
+
+	if err := w.postWithRetry(gz.Bytes()); err != nil {
+		return err
+	}
+
+	w.buf.Reset()
+	w.count = 0
+	return nil
+}
+
+func (w *HTTPWriter) postWithRetry(body []byte) error {
+	var lastErr error
+	for attempt := 0; attempt <= w.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff(attempt))
+		}
+
+		req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("remote: build request: %w", err)
+		}
+		req.Header.Set("Content-Encoding", "gzip")
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+		resp, err := w.Client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return fmt.Errorf("remote: write rejected with status %d", resp.StatusCode)
+		}
+		lastErr = fmt.Errorf("remote: write failed with status %d", resp.StatusCode)
+	}
+	return fmt.Errorf("remote: giving up after %d attempts: %w", w.MaxRetries+1, lastErr)
+}
+
+// backoff returns an exponential delay with jitter for the given attempt,
+// capped at 30s so a flapping sink doesn't stall the export indefinitely.
+func backoff(attempt int) time.Duration {
+	d := time.Duration(1<<uint(attempt)) * 100 * time.Millisecond
+	if d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Close flushes any buffered points.
+func (w *HTTPWriter) Close() error {
+	return w.flush()
+}
@@ -0,0 +1,91 @@
+package remote
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+)
+
+func TestHTTPWriter_FlushesGzippedLineProtocol(t *testing.T) {
+	var gotBody []byte
+	var gotEncoding string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("gzip.NewReader: %v", err)
+		}
+		gotBody, err = io.ReadAll(gz)
+		if err != nil {
+			t.Fatalf("read gzip body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w, err := NewHTTPWriter(srv.URL, 1)
+	if err != nil {
+		t.Fatalf("NewHTTPWriter: %v", err)
+	}
+
+	name := []byte("cpu")
+	tags := models.NewTags(map[string]string{"host": "a"})
+	seriesKey := models.AppendMakeKey(nil, name, tags)
+	key := tsm1.SeriesFieldKeyBytes(string(seriesKey), "n")
+
+	if err := w.Write(key, tsm1.Values{tsm1.NewValue(1, int64(42))}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if gotEncoding != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", gotEncoding)
+	}
+	if want := "cpu,host=a n=42i 1\n"; !strings.Contains(string(gotBody), want) {
+		t.Errorf("body = %q, want it to contain %q", gotBody, want)
+	}
+}
+
+func TestHTTPWriter_RetriesOnServerError(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w, err := NewHTTPWriter(srv.URL, 1)
+	if err != nil {
+		t.Fatalf("NewHTTPWriter: %v", err)
+	}
+
+	name := []byte("cpu")
+	tags := models.NewTags(map[string]string{"host": "a"})
+	seriesKey := models.AppendMakeKey(nil, name, tags)
+	key := tsm1.SeriesFieldKeyBytes(string(seriesKey), "n")
+
+	if err := w.Write(key, tsm1.Values{tsm1.NewValue(1, int64(42))}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("server saw %d attempts, want 2", attempts)
+	}
+}
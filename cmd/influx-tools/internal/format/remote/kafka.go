@@ -0,0 +1,110 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format/line"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+var _ format.Writer = (*KafkaWriter)(nil)
+
+// DefaultKafkaBatchSize is the number of points buffered per measurement
+// before a message is produced.
+const DefaultKafkaBatchSize = 5000
+
+// kafkaProducer is the subset of *kafka.Writer that KafkaWriter depends on,
+// narrowed so tests can substitute a fake producer instead of dialing a
+// real broker.
+type kafkaProducer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaWriter produces one message per shard-batch of line protocol to a
+// Kafka topic, keyed by measurement so messages are partitioned by
+// measurement.
+type KafkaWriter struct {
+	BatchSize int
+
+	w       kafkaProducer
+	batches map[string]*bytes.Buffer
+	counts  map[string]int
+	lineBuf []byte
+}
+
+// NewKafkaWriter returns a KafkaWriter that produces to topic on the given
+// broker, batching batchSize points per measurement between messages.
+func NewKafkaWriter(broker, topic string, batchSize int) *KafkaWriter {
+	if batchSize <= 0 {
+		batchSize = DefaultKafkaBatchSize
+	}
+	return &KafkaWriter{
+		BatchSize: batchSize,
+		w: &kafka.Writer{
+			Addr:     kafka.TCP(broker),
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+		},
+		batches: make(map[string]*bytes.Buffer),
+		counts:  make(map[string]int),
+	}
+}
+
+// Write implements format.Writer.
+func (w *KafkaWriter) Write(key []byte, values tsm1.Values) error {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	name, tags := models.ParseKeyBytes(seriesKey)
+	measurement := string(name)
+
+	buf, ok := w.batches[measurement]
+	if !ok {
+		buf = &bytes.Buffer{}
+		w.batches[measurement] = buf
+	}
+
+	for _, v := range values {
+		w.lineBuf = line.AppendLine(w.lineBuf[:0], name, tags, field, v)
+		buf.Write(w.lineBuf)
+		w.counts[measurement]++
+	}
+
+	if w.counts[measurement] >= w.BatchSize {
+		return w.produce(measurement)
+	}
+	return nil
+}
+
+func (w *KafkaWriter) produce(measurement string) error {
+	buf := w.batches[measurement]
+	if buf == nil || buf.Len() == 0 {
+		return nil
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(measurement),
+		Value: append([]byte(nil), buf.Bytes()...),
+	}
+	if err := w.w.WriteMessages(context.Background(), msg); err != nil {
+		return fmt.Errorf("remote: produce to kafka: %w", err)
+	}
+
+	buf.Reset()
+	w.counts[measurement] = 0
+	return nil
+}
+
+// Close flushes every pending measurement batch and closes the producer.
+func (w *KafkaWriter) Close() error {
+	for measurement := range w.batches {
+		if err := w.produce(measurement); err != nil {
+			return err
+		}
+	}
+	return w.w.Close()
+}
@@ -0,0 +1,97 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/engine/tsm1"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// fakeProducer records every message handed to WriteMessages instead of
+// dialing a real broker.
+type fakeProducer struct {
+	msgs   []kafka.Message
+	closed bool
+}
+
+func (p *fakeProducer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	p.msgs = append(p.msgs, msgs...)
+	return nil
+}
+
+func (p *fakeProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+func newTestKafkaWriter(batchSize int, fake *fakeProducer) *KafkaWriter {
+	return &KafkaWriter{
+		BatchSize: batchSize,
+		w:         fake,
+		batches:   make(map[string]*bytes.Buffer),
+		counts:    make(map[string]int),
+	}
+}
+
+func TestKafkaWriter_ProducesOnceBatchSizeIsReached(t *testing.T) {
+	fake := &fakeProducer{}
+	w := newTestKafkaWriter(2, fake)
+
+	name := []byte("cpu")
+	tags := models.NewTags(map[string]string{"host": "a"})
+	seriesKey := models.AppendMakeKey(nil, name, tags)
+	key := tsm1.SeriesFieldKeyBytes(string(seriesKey), "n")
+
+	if err := w.Write(key, tsm1.Values{tsm1.NewValue(1, int64(1))}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(fake.msgs) != 0 {
+		t.Fatalf("produced before batch size was reached: %d messages", len(fake.msgs))
+	}
+
+	if err := w.Write(key, tsm1.Values{tsm1.NewValue(2, int64(2))}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(fake.msgs) != 1 {
+		t.Fatalf("got %d produced messages, want 1", len(fake.msgs))
+	}
+
+	msg := fake.msgs[0]
+	if string(msg.Key) != "cpu" {
+		t.Errorf("message key = %q, want %q", msg.Key, "cpu")
+	}
+	want := "cpu,host=a n=1i 1\ncpu,host=a n=2i 2\n"
+	if string(msg.Value) != want {
+		t.Errorf("message value = %q, want %q", msg.Value, want)
+	}
+}
+
+func TestKafkaWriter_CloseFlushesPendingAndClosesProducer(t *testing.T) {
+	fake := &fakeProducer{}
+	w := newTestKafkaWriter(10, fake)
+
+	name := []byte("cpu")
+	tags := models.NewTags(map[string]string{"host": "a"})
+	seriesKey := models.AppendMakeKey(nil, name, tags)
+	key := tsm1.SeriesFieldKeyBytes(string(seriesKey), "n")
+
+	if err := w.Write(key, tsm1.Values{tsm1.NewValue(1, int64(1))}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(fake.msgs) != 0 {
+		t.Fatalf("produced before Close: %d messages", len(fake.msgs))
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(fake.msgs) != 1 {
+		t.Fatalf("got %d produced messages after Close, want 1", len(fake.msgs))
+	}
+	if !fake.closed {
+		t.Error("Close did not close the underlying producer")
+	}
+}
@@ -0,0 +1,43 @@
+package remote
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/internal/format"
+)
+
+// NewWriter dials sink and returns a format.Writer that streams exported
+// points to it, based on the URL scheme:
+//
+//	http(s)://host/write   gzip-compressed line protocol over HTTP
+//	grpc://host:port       the binary export framing over a streaming RPC
+//	kafka://broker/topic   one line-protocol message per shard-batch
+func NewWriter(sink string, database, rp string, shardDuration time.Duration, batchSize int) (format.Writer, error) {
+	u, err := url.Parse(sink)
+	if err != nil {
+		return nil, fmt.Errorf("remote: invalid -sink %q: %w", sink, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return NewHTTPWriter(sink, batchSize)
+
+	case "grpc":
+		return NewGRPCWriter(u.Host, database, rp, shardDuration)
+
+	case "kafka":
+		topic := u.Path
+		if len(topic) > 0 && topic[0] == '/' {
+			topic = topic[1:]
+		}
+		if topic == "" {
+			return nil, fmt.Errorf("remote: -sink kafka URL must include a topic, got %q", sink)
+		}
+		return NewKafkaWriter(u.Host, topic, batchSize), nil
+
+	default:
+		return nil, fmt.Errorf("remote: unsupported -sink scheme %q", u.Scheme)
+	}
+}
@@ -0,0 +1,149 @@
+// Command influx-tools provides export and diagnostic utilities for
+// InfluxDB data files.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/influxdata/influxdb/cmd/influx-tools/diag"
+	"github.com/influxdata/influxdb/cmd/influx-tools/export"
+	"github.com/influxdata/influxdb/cmd/influx-tools/server"
+	"github.com/influxdata/influxdb/cmd/influxd/run"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
+	_ "github.com/influxdata/influxdb/tsdb/engine"
+	"go.uber.org/zap"
+)
+
+func main() {
+	m := NewMain()
+	if err := m.Run(os.Args[1:]...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Main represents the program execution.
+type Main struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// NewMain returns a new instance of Main.
+func NewMain() *Main {
+	return &Main{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Run determines and runs the command specified by the CLI args.
+func (m *Main) Run(args ...string) error {
+	name, args := splitCommand(args)
+
+	switch name {
+	case "", "help", "-h", "--help":
+		fmt.Fprintln(m.Stdout, "Usage: influx-tools <command> [arguments]")
+		fmt.Fprintln(m.Stdout, "\nCommands:")
+		fmt.Fprintln(m.Stdout, "  export    export shard data as line protocol, binary, parquet, arrow or to a remote sink")
+		fmt.Fprintln(m.Stdout, "  diag      capture diagnostic profiles from a running export")
+		return nil
+	case "export":
+		c := export.NewCommand(&ossServer{logger: zap.NewNop()})
+		c.Stdout, c.Stderr = m.Stdout, m.Stderr
+		if err := c.Run(args); err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+	case "diag":
+		return m.runDiag(args)
+	default:
+		return fmt.Errorf(`unknown command "%s"`+"\nRun 'influx-tools help' for usage", name)
+	}
+
+	return nil
+}
+
+// runDiag dispatches diag's subcommands; "profile" is the only one so
+// far.
+func (m *Main) runDiag(args []string) error {
+	name, args := splitCommand(args)
+
+	switch name {
+	case "profile":
+		c := diag.NewCommand()
+		c.Stdout, c.Stderr = m.Stdout, m.Stderr
+		if err := c.Run(args); err != nil {
+			return fmt.Errorf("diag profile failed: %w", err)
+		}
+	default:
+		return fmt.Errorf(`unknown diag command "%s"`+"\nRun 'influx-tools help' for usage", name)
+	}
+
+	return nil
+}
+
+// splitCommand separates the subcommand name from its remaining
+// arguments, e.g. ["diag", "profile", "-addr", "..."] -> ("diag",
+// ["profile", "-addr", "..."]).
+func splitCommand(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	return args[0], args[1:]
+}
+
+// ossServer is the server.Interface implementation export uses to
+// resolve a database's shard directory and retention policy metadata from
+// an influxd config file.
+type ossServer struct {
+	logger *zap.Logger
+	config *run.Config
+	client *meta.Client
+}
+
+func (s *ossServer) Open(path string) error {
+	if path == "" {
+		return errors.New("missing config file")
+	}
+
+	s.config = run.NewConfig()
+	if err := s.config.FromTomlFile(path); err != nil {
+		return err
+	}
+	if err := s.config.Validate(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	s.client = meta.NewClient(s.config.Meta)
+	return s.client.Open()
+}
+
+func (s *ossServer) Close() {
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+}
+
+func (s *ossServer) MetaClient() server.MetaClient { return &ossMetaClient{s.client} }
+func (s *ossServer) TSDBConfig() tsdb.Config       { return s.config.Data }
+func (s *ossServer) Logger() *zap.Logger           { return s.logger }
+
+// ossMetaClient adapts *meta.Client to server.MetaClient. NodeID is always
+// 0, and NodeShardGroupsByTimeRange returns every shard group in the time
+// range, unfiltered by node.
+type ossMetaClient struct {
+	*meta.Client
+}
+
+func (*ossMetaClient) NodeID() uint64 { return 0 }
+
+func (c *ossMetaClient) NodeShardGroupsByTimeRange(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+	return c.ShardGroupsByTimeRange(database, policy, min, max)
+}